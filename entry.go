@@ -11,11 +11,20 @@ const (
 	EntryTypeDouble               = 0x01
 	EntryTypeString               = 0x02
 	EntryTypeRawData              = 0x03
+	EntryTypeInteger              = 0x04
+	EntryTypeFloat                = 0x05
 	EntryTypeBooleanArr           = 0x10
 	EntryTypeDoubleArr            = 0x11
 	EntryTypeStringArr            = 0x12
+	EntryTypeIntegerArr           = 0x13
+	EntryTypeFloatArr             = 0x14
 	EntryTypeRPCDef               = 0x20
-	EntryTypeUndef                = 0xFF
+
+	EntryTypeBooleanArrWide = 0x30
+	EntryTypeDoubleArrWide  = 0x31
+	EntryTypeStringArrWide  = 0x32
+
+	EntryTypeUndef = 0xFF
 
 	EntryFlagTemporary  EntryFlag = 0x00
 	EntryFlagPersistent           = 0x01
@@ -115,6 +124,20 @@ func DecodeEntryValue(r io.Reader, entryType EntryType) (EntryValue, error) {
 		return DecodeDoubleArray(r)
 	case EntryTypeStringArr:
 		return DecodeStringArray(r)
+	case EntryTypeInteger:
+		return DecodeInteger(r)
+	case EntryTypeFloat:
+		return DecodeFloat(r)
+	case EntryTypeIntegerArr:
+		return DecodeIntegerArray(r)
+	case EntryTypeFloatArr:
+		return DecodeFloatArray(r)
+	case EntryTypeBooleanArrWide:
+		return DecodeBooleanArrayWide(r)
+	case EntryTypeDoubleArrWide:
+		return DecodeDoubleArrayWide(r)
+	case EntryTypeStringArrWide:
+		return DecodeStringArrayWide(r)
 	default:
 		return nil, ErrEntryNoSuchType
 	}
@@ -126,21 +149,11 @@ type ValueBoolean struct {
 }
 
 func DecodeBoolean(r io.Reader) (*ValueBoolean, error) {
-	val := make([]byte, 1)
-	_, readErr := io.ReadFull(r, val)
-	if readErr != nil {
-		return nil, readErr
-	}
-	entry := &ValueBoolean{RawValue: val}
-	if entry.RawValue[0] == BoolFalse {
-		entry.Value = false
-		return entry, nil
-	} else if entry.RawValue[0] == BoolTrue {
-		entry.Value = true
-		return entry, nil
-	} else {
-		return nil, ErrEntryDataInvalid
+	entry := &ValueBoolean{}
+	if err := decodeBooleanFrom(r, entry); err != nil {
+		return nil, err
 	}
+	return entry, nil
 }
 
 func BuildBoolean(value bool) *ValueBoolean {
@@ -178,19 +191,11 @@ type ValueString struct {
 }
 
 func DecodeString(r io.Reader) (*ValueString, error) {
-	uleb, ulebData, ulebErr := DecodeAndSaveULEB128(r)
-	if ulebErr != nil {
-		return nil, ulebErr
+	entry := &ValueString{}
+	if err := decodeStringFrom(r, entry); err != nil {
+		return nil, err
 	}
-	data := make([]byte, uleb)
-	_, readErr := io.ReadFull(r, data)
-	if readErr != nil {
-		return nil, readErr
-	}
-	return &ValueString{
-		Value:    string(data),
-		RawValue: append(ulebData, data...),
-	}, nil
+	return entry, nil
 }
 
 func BuildString(value string) *ValueString {
@@ -224,15 +229,11 @@ type ValueDouble struct {
 }
 
 func DecodeDouble(r io.Reader) (*ValueDouble, error) {
-	data := make([]byte, 8)
-	_, readErr := io.ReadFull(r, data)
-	if readErr != nil {
-		return nil, readErr
+	entry := &ValueDouble{}
+	if err := decodeDoubleFrom(r, entry); err != nil {
+		return nil, err
 	}
-	return &ValueDouble{
-		Value:    BytesToFloat64(data),
-		RawValue: data,
-	}, nil
+	return entry, nil
 }
 
 func BuildDouble(value float64) *ValueDouble {
@@ -264,19 +265,11 @@ type ValueRaw struct {
 }
 
 func DecodeRaw(r io.Reader) (*ValueRaw, error) {
-	uleb, ulebData, ulebErr := DecodeAndSaveULEB128(r)
-	if ulebErr != nil {
-		return nil, ulebErr
+	entry := &ValueRaw{}
+	if err := decodeRawFrom(r, entry); err != nil {
+		return nil, err
 	}
-	data := make([]byte, uleb)
-	_, readErr := io.ReadFull(r, data)
-	if readErr != nil {
-		return nil, readErr
-	}
-	return &ValueRaw{
-		Value:    data,
-		RawValue: append(ulebData, data...),
-	}, nil
+	return entry, nil
 }
 
 func BuildRaw(value []byte) *ValueRaw {
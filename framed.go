@@ -0,0 +1,125 @@
+package ntgo
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+)
+
+// maxFrameBodyLength bounds the type+payload a single record may claim,
+// so a corrupt or truncated length field can't drive ReadRecord into
+// allocating gigabytes before the CRC check ever gets a chance to fail it.
+const maxFrameBodyLength = 16 << 20
+
+var (
+	ErrCRCMismatch   = errors.New("framed: crc32 mismatch")
+	ErrNotSeekable   = errors.New("framed: underlying stream is not seekable")
+	ErrFrameTooLarge = errors.New("framed: record length exceeds maxFrameBodyLength")
+)
+
+// FramedEncoder chains the CRC-32 of each record into the seed for the
+// next, so corruption in an earlier record is detectable even if a later
+// record's own bytes are intact.
+type FramedEncoder struct {
+	w       io.Writer
+	lastCRC uint32
+}
+
+func NewFramedEncoder(w io.Writer) *FramedEncoder {
+	return &FramedEncoder{w: w}
+}
+
+func (enc *FramedEncoder) WriteRecord(entryType EntryType, payload []byte) error {
+	body := make([]byte, 0, len(payload)+1)
+	body = append(body, byte(entryType))
+	body = append(body, payload...)
+
+	crc := crc32.Update(enc.lastCRC, crc32.IEEETable, body)
+
+	frame := EncodeULEB128(uint32(len(body)))
+	frame = append(frame, body...)
+	crcBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(crcBytes, crc)
+	frame = append(frame, crcBytes...)
+
+	if _, err := enc.w.Write(frame); err != nil {
+		return err
+	}
+	enc.lastCRC = crc
+	return nil
+}
+
+// FramedDecoder reads back records written by a FramedEncoder, verifying
+// each record's CRC-32, seeded with dec.lastCRC, against its trailer.
+type FramedDecoder struct {
+	r       io.Reader
+	lastCRC uint32
+}
+
+func NewFramedDecoder(r io.Reader) *FramedDecoder {
+	return &FramedDecoder{r: r}
+}
+
+func (dec *FramedDecoder) ReadRecord() (EntryType, []byte, error) {
+	length, _, ulebErr := DecodeAndSaveULEB128(dec.r)
+	if ulebErr != nil {
+		return EntryTypeUndef, nil, ulebErr
+	}
+	if length > maxFrameBodyLength {
+		return EntryTypeUndef, nil, ErrFrameTooLarge
+	}
+
+	body := make([]byte, length)
+	if _, readErr := io.ReadFull(dec.r, body); readErr != nil {
+		return EntryTypeUndef, nil, readErr
+	}
+
+	crcBytes := make([]byte, 4)
+	if _, readErr := io.ReadFull(dec.r, crcBytes); readErr != nil {
+		return EntryTypeUndef, nil, readErr
+	}
+
+	computedCRC := crc32.Update(dec.lastCRC, crc32.IEEETable, body)
+	declaredCRC := binary.BigEndian.Uint32(crcBytes)
+
+	// Resync off the trailer's declared CRC even on mismatch, not just on
+	// success: that's the value the encoder actually chained forward, so
+	// anchoring off it lets a later, untouched record keep validating
+	// correctly instead of failing forever after one bad record.
+	dec.lastCRC = declaredCRC
+
+	if computedCRC != declaredCRC {
+		return EntryTypeUndef, nil, ErrCRCMismatch
+	}
+	if len(body) < 1 {
+		return EntryTypeUndef, nil, ErrEntryDataInvalid
+	}
+	return EntryType(body[0]), body[1:], nil
+}
+
+// Skip reads past the next record without returning an error for a failed
+// checksum, leaving the stream positioned at the record that follows.
+func (dec *FramedDecoder) Skip() error {
+	_, _, err := dec.ReadRecord()
+	if err != nil && err != ErrCRCMismatch {
+		return err
+	}
+	return nil
+}
+
+// Seek repositions the decoder on the underlying stream when it supports
+// io.Seeker, resetting the CRC chain since the record at the new offset is
+// not necessarily the successor of the last one read.
+func (dec *FramedDecoder) Seek(offset int64, whence int) (int64, error) {
+	seeker, ok := dec.r.(io.Seeker)
+	if !ok {
+		return 0, ErrNotSeekable
+	}
+	pos, err := seeker.Seek(offset, whence)
+	if err != nil {
+		return pos, err
+	}
+	dec.lastCRC = 0
+	return pos, nil
+}
@@ -0,0 +1,110 @@
+package ntgo
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFramedEncodeDecodeRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewFramedEncoder(&buf)
+
+	records := []struct {
+		entryType EntryType
+		payload   []byte
+	}{
+		{EntryTypeBoolean, BuildBoolean(true).RawValue},
+		{EntryTypeString, BuildString("hello").RawValue},
+		{EntryTypeDouble, BuildDouble(42).RawValue},
+	}
+	for _, record := range records {
+		if err := enc.WriteRecord(record.entryType, record.payload); err != nil {
+			t.Fatalf("WriteRecord: %v", err)
+		}
+	}
+
+	dec := NewFramedDecoder(&buf)
+	for i, want := range records {
+		entryType, payload, err := dec.ReadRecord()
+		if err != nil {
+			t.Fatalf("record %d: ReadRecord: %v", i, err)
+		}
+		if entryType != want.entryType {
+			t.Errorf("record %d: got type %v, want %v", i, entryType, want.entryType)
+		}
+		if !bytes.Equal(payload, want.payload) {
+			t.Errorf("record %d: got payload %v, want %v", i, payload, want.payload)
+		}
+	}
+}
+
+func TestFramedDecoderDetectsCorruption(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewFramedEncoder(&buf)
+	if err := enc.WriteRecord(EntryTypeString, BuildString("hello").RawValue); err != nil {
+		t.Fatalf("WriteRecord: %v", err)
+	}
+
+	corrupted := buf.Bytes()
+	corrupted[len(corrupted)-1] ^= 0xFF
+
+	dec := NewFramedDecoder(bytes.NewReader(corrupted))
+	if _, _, err := dec.ReadRecord(); err != ErrCRCMismatch {
+		t.Fatalf("got err %v, want ErrCRCMismatch", err)
+	}
+}
+
+func TestFramedDecoderSkipRecoversAfterBadRecord(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewFramedEncoder(&buf)
+
+	records := [][]byte{
+		BuildString("first").RawValue,
+		BuildString("second").RawValue,
+		BuildString("third").RawValue,
+	}
+	offsets := make([]int, len(records))
+	for i, payload := range records {
+		offsets[i] = buf.Len()
+		if err := enc.WriteRecord(EntryTypeString, payload); err != nil {
+			t.Fatalf("WriteRecord %d: %v", i, err)
+		}
+	}
+
+	corrupted := buf.Bytes()
+	// Flip a byte inside record 2's body, leaving records 1 and 3 intact.
+	corrupted[offsets[1]+2] ^= 0xFF
+
+	dec := NewFramedDecoder(bytes.NewReader(corrupted))
+
+	_, payload, err := dec.ReadRecord()
+	if err != nil {
+		t.Fatalf("record 1: ReadRecord: %v", err)
+	}
+	if !bytes.Equal(payload, records[0]) {
+		t.Fatalf("record 1: got %v, want %v", payload, records[0])
+	}
+
+	if err := dec.Skip(); err != nil {
+		t.Fatalf("Skip record 2: %v", err)
+	}
+
+	_, payload, err = dec.ReadRecord()
+	if err != nil {
+		t.Fatalf("record 3: ReadRecord: %v", err)
+	}
+	if !bytes.Equal(payload, records[2]) {
+		t.Fatalf("record 3: got %v, want %v", payload, records[2])
+	}
+}
+
+func TestFramedDecoderRejectsOversizedLength(t *testing.T) {
+	var frame []byte
+	frame = append(frame, EncodeULEB128(maxFrameBodyLength+1)...)
+	frame = append(frame, make([]byte, 8)...)
+
+	dec := NewFramedDecoder(bytes.NewReader(frame))
+	if _, _, err := dec.ReadRecord(); err != ErrFrameTooLarge {
+		t.Fatalf("got err %v, want ErrFrameTooLarge", err)
+	}
+}
@@ -0,0 +1,300 @@
+package ntgo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	ErrMarshalInvalidKind  = errors.New("marshal: unsupported go kind")
+	ErrMarshalNotStruct    = errors.New("marshal: value must be a struct or pointer to struct")
+	ErrUnmarshalNotPointer = errors.New("unmarshal: target must be a non-nil pointer to struct")
+)
+
+type ntTag struct {
+	name       string
+	persistent bool
+	omitempty  bool
+	skip       bool
+}
+
+func parseNTTag(field reflect.StructField) ntTag {
+	raw, ok := field.Tag.Lookup("nt")
+	if !ok {
+		return ntTag{name: field.Name}
+	}
+	if raw == "-" {
+		return ntTag{skip: true}
+	}
+	parts := strings.Split(raw, ",")
+	tag := ntTag{name: parts[0]}
+	if tag.name == "" {
+		tag.name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		switch opt {
+		case "persistent":
+			tag.persistent = true
+		case "omitempty":
+			tag.omitempty = true
+		}
+	}
+	return tag
+}
+
+// Marshal walks the exported fields of v, a struct or pointer to struct,
+// and produces one Entry per field whose name is taken from its `nt` struct
+// tag (falling back to the Go field name). Nested structs are flattened
+// into dotted names, e.g. `Drive.LeftEncoder`.
+func Marshal(v interface{}) ([]Entry, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, ErrMarshalNotStruct
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, ErrMarshalNotStruct
+	}
+	return marshalStruct(rv, "")
+}
+
+func marshalStruct(rv reflect.Value, prefix string) ([]Entry, error) {
+	rt := rv.Type()
+	entries := make([]Entry, 0, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := parseNTTag(field)
+		if tag.skip {
+			continue
+		}
+
+		fv := rv.Field(i)
+		name := tag.name
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		if fv.Kind() == reflect.Struct {
+			nested, err := marshalStruct(fv, name)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, nested...)
+			continue
+		}
+
+		if tag.omitempty && fv.IsZero() {
+			continue
+		}
+
+		value, entryType, err := marshalValue(fv)
+		if err != nil {
+			return nil, fmt.Errorf("marshal: field %q: %w", name, err)
+		}
+
+		flags := EntryFlagTemporary
+		if tag.persistent {
+			flags = EntryFlagPersistent
+		}
+
+		entries = append(entries, Entry{
+			Name:  BuildString(name),
+			Type:  entryType,
+			Flags: flags,
+			Value: value,
+		})
+	}
+	return entries, nil
+}
+
+func marshalValue(fv reflect.Value) (EntryValue, EntryType, error) {
+	switch fv.Kind() {
+	case reflect.Bool:
+		return BuildBoolean(fv.Bool()), EntryTypeBoolean, nil
+	case reflect.Float64, reflect.Float32:
+		return BuildDouble(fv.Float()), EntryTypeDouble, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return BuildDouble(float64(fv.Int())), EntryTypeDouble, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return BuildDouble(float64(fv.Uint())), EntryTypeDouble, nil
+	case reflect.String:
+		return BuildString(fv.String()), EntryTypeString, nil
+	case reflect.Slice:
+		return marshalSlice(fv)
+	default:
+		return nil, EntryTypeUndef, ErrMarshalInvalidKind
+	}
+}
+
+func marshalSlice(fv reflect.Value) (EntryValue, EntryType, error) {
+	switch fv.Type().Elem().Kind() {
+	case reflect.Uint8:
+		return BuildRaw(fv.Bytes()), EntryTypeRawData, nil
+	case reflect.Bool:
+		values := make([]*ValueBoolean, fv.Len())
+		for i := range values {
+			values[i] = BuildBoolean(fv.Index(i).Bool())
+		}
+		return BuildBooleanArray(values), EntryTypeBooleanArr, nil
+	case reflect.Float64:
+		values := make([]*ValueDouble, fv.Len())
+		for i := range values {
+			values[i] = BuildDouble(fv.Index(i).Float())
+		}
+		return BuildDoubleArray(values), EntryTypeDoubleArr, nil
+	case reflect.String:
+		values := make([]*ValueString, fv.Len())
+		for i := range values {
+			values[i] = BuildString(fv.Index(i).String())
+		}
+		return BuildStringArray(values), EntryTypeStringArr, nil
+	default:
+		return nil, EntryTypeUndef, ErrMarshalInvalidKind
+	}
+}
+
+// Unmarshal is the inverse of Marshal: it looks each Entry up by its dotted
+// name and assigns it into the matching field of v, a pointer to struct.
+func Unmarshal(entries []Entry, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrUnmarshalNotPointer
+	}
+
+	byName := make(map[string]Entry, len(entries))
+	for _, entry := range entries {
+		if entry.Name == nil {
+			continue
+		}
+		byName[entry.Name.Value] = entry
+	}
+
+	return unmarshalStruct(rv.Elem(), "", byName)
+}
+
+func unmarshalStruct(rv reflect.Value, prefix string, byName map[string]Entry) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := parseNTTag(field)
+		if tag.skip {
+			continue
+		}
+
+		fv := rv.Field(i)
+		name := tag.name
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		if fv.Kind() == reflect.Struct {
+			if err := unmarshalStruct(fv, name, byName); err != nil {
+				return err
+			}
+			continue
+		}
+
+		entry, ok := byName[name]
+		if !ok {
+			continue
+		}
+		if err := unmarshalValue(fv, entry); err != nil {
+			return fmt.Errorf("unmarshal: field %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func unmarshalValue(fv reflect.Value, entry Entry) error {
+	switch fv.Kind() {
+	case reflect.Bool:
+		value, ok := entry.Value.(*ValueBoolean)
+		if !ok {
+			return ErrEntryTypeCastInvalid
+		}
+		fv.SetBool(value.Value)
+	case reflect.Float64, reflect.Float32:
+		value, ok := entry.Value.(*ValueDouble)
+		if !ok {
+			return ErrEntryTypeCastInvalid
+		}
+		fv.SetFloat(value.Value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, ok := entry.Value.(*ValueDouble)
+		if !ok {
+			return ErrEntryTypeCastInvalid
+		}
+		fv.SetInt(int64(value.Value))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, ok := entry.Value.(*ValueDouble)
+		if !ok {
+			return ErrEntryTypeCastInvalid
+		}
+		fv.SetUint(uint64(value.Value))
+	case reflect.String:
+		value, ok := entry.Value.(*ValueString)
+		if !ok {
+			return ErrEntryTypeCastInvalid
+		}
+		fv.SetString(value.Value)
+	case reflect.Slice:
+		return unmarshalSlice(fv, entry)
+	default:
+		return ErrMarshalInvalidKind
+	}
+	return nil
+}
+
+func unmarshalSlice(fv reflect.Value, entry Entry) error {
+	switch fv.Type().Elem().Kind() {
+	case reflect.Uint8:
+		value, ok := entry.Value.(*ValueRaw)
+		if !ok {
+			return ErrEntryTypeCastInvalid
+		}
+		fv.SetBytes(value.Value)
+	case reflect.Bool:
+		array, ok := entry.Value.(*ValueBooleanArray)
+		if !ok {
+			return ErrEntryTypeCastInvalid
+		}
+		out := reflect.MakeSlice(fv.Type(), len(array.elements), len(array.elements))
+		for i, element := range array.elements {
+			out.Index(i).SetBool(element.Value)
+		}
+		fv.Set(out)
+	case reflect.Float64:
+		array, ok := entry.Value.(*ValueDoubleArray)
+		if !ok {
+			return ErrEntryTypeCastInvalid
+		}
+		out := reflect.MakeSlice(fv.Type(), len(array.elements), len(array.elements))
+		for i, element := range array.elements {
+			out.Index(i).SetFloat(element.Value)
+		}
+		fv.Set(out)
+	case reflect.String:
+		array, ok := entry.Value.(*ValueStringArray)
+		if !ok {
+			return ErrEntryTypeCastInvalid
+		}
+		out := reflect.MakeSlice(fv.Type(), len(array.elements), len(array.elements))
+		for i, element := range array.elements {
+			out.Index(i).SetString(element.Value)
+		}
+		fv.Set(out)
+	default:
+		return ErrMarshalInvalidKind
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package ntgo
+
+import "testing"
+
+type driveConfig struct {
+	Encoder  float64 `nt:"Encoder"`
+	Inverted bool    `nt:"Inverted,persistent"`
+}
+
+type robotConfig struct {
+	Name    string      `nt:"Name"`
+	Drive   driveConfig `nt:"Drive"`
+	Ignored string      `nt:"-"`
+}
+
+func TestMarshalUnmarshalRoundTrip(t *testing.T) {
+	in := robotConfig{
+		Name: "kitbot",
+		Drive: driveConfig{
+			Encoder:  12.5,
+			Inverted: true,
+		},
+		Ignored: "should not round-trip",
+	}
+
+	entries, err := Marshal(&in)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var gotPersistent bool
+	for _, entry := range entries {
+		if entry.Name.Value == "Drive.Inverted" {
+			gotPersistent = entry.Flags == EntryFlagPersistent
+		}
+	}
+	if !gotPersistent {
+		t.Error("Drive.Inverted entry missing persistent flag")
+	}
+
+	var out robotConfig
+	if err := Unmarshal(entries, &out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if out.Name != in.Name {
+		t.Errorf("Name: got %q, want %q", out.Name, in.Name)
+	}
+	if out.Drive != in.Drive {
+		t.Errorf("Drive: got %+v, want %+v", out.Drive, in.Drive)
+	}
+	if out.Ignored != "" {
+		t.Errorf("Ignored: got %q, want empty (tagged nt:\"-\")", out.Ignored)
+	}
+}
+
+func TestUnmarshalRejectsTypeMismatch(t *testing.T) {
+	entries := []Entry{
+		{
+			Name:  BuildString("Name"),
+			Type:  EntryTypeBoolean,
+			Value: BuildBoolean(true),
+		},
+	}
+	var out robotConfig
+	if err := Unmarshal(entries, &out); err == nil {
+		t.Fatal("expected error unmarshaling a boolean entry into a string field")
+	}
+}
@@ -0,0 +1,87 @@
+package ntgo
+
+import "io"
+
+// MultiReadCloser chains a sequence of io.ReadClosers into a single
+// io.ReadCloser, closing each as it is exhausted and advancing to the
+// next. Unlike io.MultiReader it owns its readers and is safe to use as
+// the single source for a long-lived decoder.
+type MultiReadCloser struct {
+	readers []io.ReadCloser
+	index   int
+}
+
+func NewMultiReadCloser(readers ...io.ReadCloser) *MultiReadCloser {
+	return &MultiReadCloser{readers: readers}
+}
+
+func (m *MultiReadCloser) Read(p []byte) (int, error) {
+	for m.index < len(m.readers) {
+		n, readErr := m.readers[m.index].Read(p)
+		if n > 0 {
+			return n, nil
+		}
+		if readErr == nil {
+			// Per io.Reader, (0, nil) means no progress yet, not EOF -
+			// retry the same reader instead of advancing past it.
+			continue
+		}
+		if readErr != io.EOF {
+			return 0, readErr
+		}
+		if closeErr := m.readers[m.index].Close(); closeErr != nil {
+			return 0, closeErr
+		}
+		m.index++
+	}
+	return 0, io.EOF
+}
+
+func (m *MultiReadCloser) Close() error {
+	var firstErr error
+	for ; m.index < len(m.readers); m.index++ {
+		if err := m.readers[m.index].Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Segment returns the index of the reader currently being consumed.
+func (m *MultiReadCloser) Segment() int {
+	return m.index
+}
+
+// EntryStreamDecoder presents a sequence of underlying readers - rotated
+// on-disk log segments, reconnected TCP sessions, and the like - as one
+// continuous entry stream. Reads that straddle a segment boundary are
+// satisfied transparently by MultiReadCloser, so a partial ULEB128 length
+// or half-read array element is completed from the next reader rather than
+// surfacing a premature EOF.
+type EntryStreamDecoder struct {
+	mr     *MultiReadCloser
+	offset int64
+}
+
+func NewEntryStreamDecoder(readers ...io.ReadCloser) *EntryStreamDecoder {
+	return &EntryStreamDecoder{mr: NewMultiReadCloser(readers...)}
+}
+
+func (d *EntryStreamDecoder) Decode() (EntryValue, EntryType, error) {
+	value, entryType, err := DecodeEntryValueAndType(d.mr)
+	if err != nil {
+		return nil, EntryTypeUndef, err
+	}
+	d.offset += int64(len(value.GetRaw()) + 1)
+	return value, entryType, nil
+}
+
+// Position reports which segment is currently being read and the decoder's
+// byte offset within the logical stream, so a caller can checkpoint.
+func (d *EntryStreamDecoder) Position() (segment int, offset int64) {
+	return d.mr.Segment(), d.offset
+}
+
+func (d *EntryStreamDecoder) Close() error {
+	return d.mr.Close()
+}
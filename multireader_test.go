@@ -0,0 +1,100 @@
+package ntgo
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+type nopCloser struct {
+	*bytes.Reader
+}
+
+func (nopCloser) Close() error { return nil }
+
+func newSegment(data []byte) io.ReadCloser {
+	return nopCloser{bytes.NewReader(data)}
+}
+
+func TestMultiReadCloserSpansSegmentBoundary(t *testing.T) {
+	raw := BuildString("hello").RawValue
+	if len(raw) < 2 {
+		t.Fatalf("need at least 2 bytes to split, got %d", len(raw))
+	}
+
+	// Split right after the one-byte ULEB128 length prefix, so the length
+	// comes from the first segment and the string bytes from the second.
+	mr := NewMultiReadCloser(newSegment(raw[:1]), newSegment(raw[1:]))
+
+	decoded, err := DecodeString(mr)
+	if err != nil {
+		t.Fatalf("DecodeString: %v", err)
+	}
+	if decoded.Value != "hello" {
+		t.Errorf("got %q, want %q", decoded.Value, "hello")
+	}
+}
+
+func TestEntryStreamDecoderAcrossSegments(t *testing.T) {
+	framed := append([]byte{byte(EntryTypeBoolean)}, BuildBoolean(true).RawValue...)
+
+	// Split between the type byte and the value so the decoder must pull
+	// from both segments to produce the entry type and payload.
+	split := len(framed) - 1
+	decoder := NewEntryStreamDecoder(
+		newSegment(framed[:split]),
+		newSegment(framed[split:]),
+	)
+
+	value, entryType, err := decoder.Decode()
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if entryType != EntryTypeBoolean {
+		t.Errorf("got type %v, want %v", entryType, EntryTypeBoolean)
+	}
+	if !value.(*ValueBoolean).Value {
+		t.Error("got false, want true")
+	}
+}
+
+type flakyReader struct {
+	calls int
+	data  []byte
+}
+
+func (f *flakyReader) Read(p []byte) (int, error) {
+	f.calls++
+	if f.calls == 1 {
+		return 0, nil
+	}
+	n := copy(p, f.data)
+	return n, io.EOF
+}
+
+type flakyCloser struct {
+	*flakyReader
+	closed bool
+}
+
+func (f *flakyCloser) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestMultiReadCloserRetriesOnZeroNilRead(t *testing.T) {
+	flaky := &flakyCloser{flakyReader: &flakyReader{data: []byte("ok")}}
+	mr := NewMultiReadCloser(flaky)
+
+	buf := make([]byte, 2)
+	n, err := mr.Read(buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if n != 2 || string(buf) != "ok" {
+		t.Fatalf("got %q (n=%d), want %q", buf[:n], n, "ok")
+	}
+	if flaky.closed {
+		t.Error("reader was closed after a (0, nil) read instead of being retried")
+	}
+}
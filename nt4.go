@@ -0,0 +1,534 @@
+package ntgo
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/imdario/mergo"
+)
+
+// NTVersion selects which wire format a Codec speaks: NT3's single-byte
+// array length prefix and legacy type set, or NT4's ULEB128-prefixed wide
+// arrays plus the Integer/Float types.
+type NTVersion int
+
+const (
+	NT3 NTVersion = iota
+	NT4
+)
+
+// Codec pins a caller to one NetworkTables protocol version so the same
+// stream isn't decoded as a mix of NT3 and NT4 framing. It does not stop a
+// caller from building an NT4-only value (e.g. BuildBooleanArrayWide)
+// directly and writing it out under a mismatched version - encode-side
+// callers should run the EntryType they're about to write through
+// ValidateType first to enforce the same boundary DecodeValue applies.
+type Codec struct {
+	Version NTVersion
+}
+
+func NewCodec(version NTVersion) *Codec {
+	return &Codec{Version: version}
+}
+
+// ValidateType reports whether entryType is legal under c.Version.
+func (c *Codec) ValidateType(entryType EntryType) error {
+	if c.Version == NT3 {
+		switch entryType {
+		case EntryTypeBoolean, EntryTypeDouble, EntryTypeString, EntryTypeRawData,
+			EntryTypeBooleanArr, EntryTypeDoubleArr, EntryTypeStringArr:
+			return nil
+		default:
+			return ErrEntryNoSuchType
+		}
+	}
+	return nil
+}
+
+func (c *Codec) DecodeValue(r io.Reader, entryType EntryType) (EntryValue, error) {
+	if err := c.ValidateType(entryType); err != nil {
+		return nil, err
+	}
+	return DecodeEntryValue(r, entryType)
+}
+
+func Int64ToBytes(value int64) []byte {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, uint64(value))
+	return data
+}
+
+func BytesToInt64(data []byte) int64 {
+	return int64(binary.BigEndian.Uint64(data))
+}
+
+func Float32ToBytes(value float32) []byte {
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint32(data, math.Float32bits(value))
+	return data
+}
+
+func BytesToFloat32(data []byte) float32 {
+	return math.Float32frombits(binary.BigEndian.Uint32(data))
+}
+
+type ValueInteger struct {
+	Value    int64
+	RawValue []byte
+}
+
+func DecodeInteger(r io.Reader) (*ValueInteger, error) {
+	data := make([]byte, 8)
+	_, readErr := io.ReadFull(r, data)
+	if readErr != nil {
+		return nil, readErr
+	}
+	return &ValueInteger{
+		Value:    BytesToInt64(data),
+		RawValue: data,
+	}, nil
+}
+
+func BuildInteger(value int64) *ValueInteger {
+	return &ValueInteger{
+		Value:    value,
+		RawValue: Int64ToBytes(value),
+	}
+}
+
+func (entry *ValueInteger) UpdateRaw(r io.Reader) error {
+	newEntry, newErr := DecodeInteger(r)
+	if newErr != nil {
+		return newErr
+	}
+	return mergo.MergeWithOverwrite(entry, *newEntry)
+}
+
+func (entry *ValueInteger) GetRaw() []byte {
+	return entry.RawValue
+}
+
+func (entry *ValueInteger) UpdateValue(value int64) error {
+	return mergo.MergeWithOverwrite(entry, *BuildInteger(value))
+}
+
+type ValueFloat struct {
+	Value    float32
+	RawValue []byte
+}
+
+func DecodeFloat(r io.Reader) (*ValueFloat, error) {
+	data := make([]byte, 4)
+	_, readErr := io.ReadFull(r, data)
+	if readErr != nil {
+		return nil, readErr
+	}
+	return &ValueFloat{
+		Value:    BytesToFloat32(data),
+		RawValue: data,
+	}, nil
+}
+
+func BuildFloat(value float32) *ValueFloat {
+	return &ValueFloat{
+		Value:    value,
+		RawValue: Float32ToBytes(value),
+	}
+}
+
+func (entry *ValueFloat) UpdateRaw(r io.Reader) error {
+	newEntry, newErr := DecodeFloat(r)
+	if newErr != nil {
+		return newErr
+	}
+	return mergo.MergeWithOverwrite(entry, *newEntry)
+}
+
+func (entry *ValueFloat) GetRaw() []byte {
+	return entry.RawValue
+}
+
+func (entry *ValueFloat) UpdateValue(value float32) error {
+	return mergo.MergeWithOverwrite(entry, *BuildFloat(value))
+}
+
+type ValueIntegerArray struct {
+	elements []*ValueInteger
+}
+
+func DecodeIntegerArray(r io.Reader) (*ValueIntegerArray, error) {
+	count, _, ulebErr := DecodeAndSaveULEB128(r)
+	if ulebErr != nil {
+		return nil, ulebErr
+	}
+	elements := make([]*ValueInteger, count)
+	for i := range elements {
+		integer, decodeErr := DecodeInteger(r)
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+		elements[i] = integer
+	}
+	return &ValueIntegerArray{elements: elements}, nil
+}
+
+func BuildIntegerArray(values []*ValueInteger) *ValueIntegerArray {
+	return &ValueIntegerArray{elements: values}
+}
+
+// Get satisfies EntryValueArray but can only reach the first 256 elements;
+// callers that need the full range this type was built for should use
+// GetWide instead.
+func (array *ValueIntegerArray) Get(index uint8) (EntryValue, error) {
+	return array.GetWide(uint32(index))
+}
+
+// Update satisfies EntryValueArray but can only reach the first 256
+// elements; callers that need the full range this type was built for
+// should use UpdateWide instead.
+func (array *ValueIntegerArray) Update(index uint8, entry EntryValue) error {
+	return array.UpdateWide(uint32(index), entry)
+}
+
+func (array *ValueIntegerArray) GetWide(index uint32) (EntryValue, error) {
+	if index >= uint32(len(array.elements)) {
+		return nil, ErrArrayIndexOutOfBounds
+	}
+	return array.elements[index], nil
+}
+
+func (array *ValueIntegerArray) UpdateWide(index uint32, entry EntryValue) error {
+	integer, ok := entry.(*ValueInteger)
+	if !ok {
+		return ErrEntryCastInvalid
+	}
+	if index >= uint32(len(array.elements)) {
+		return ErrArrayIndexOutOfBounds
+	}
+	return mergo.MergeWithOverwrite(array.elements[index], *integer)
+}
+
+func (array *ValueIntegerArray) Add(entry EntryValue) error {
+	integer, ok := entry.(*ValueInteger)
+	if !ok {
+		return ErrEntryCastInvalid
+	}
+	if uint32(len(array.elements)) == math.MaxUint32 {
+		return ErrArrayOutOfSpace
+	}
+	array.elements = append(array.elements, integer)
+	return nil
+}
+
+func (array *ValueIntegerArray) GetRaw() []byte {
+	data := EncodeULEB128(uint32(len(array.elements)))
+	for _, element := range array.elements {
+		data = append(data, element.RawValue...)
+	}
+	return data
+}
+
+type ValueFloatArray struct {
+	elements []*ValueFloat
+}
+
+func DecodeFloatArray(r io.Reader) (*ValueFloatArray, error) {
+	count, _, ulebErr := DecodeAndSaveULEB128(r)
+	if ulebErr != nil {
+		return nil, ulebErr
+	}
+	elements := make([]*ValueFloat, count)
+	for i := range elements {
+		float, decodeErr := DecodeFloat(r)
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+		elements[i] = float
+	}
+	return &ValueFloatArray{elements: elements}, nil
+}
+
+func BuildFloatArray(values []*ValueFloat) *ValueFloatArray {
+	return &ValueFloatArray{elements: values}
+}
+
+// Get satisfies EntryValueArray but can only reach the first 256 elements;
+// callers that need the full range this type was built for should use
+// GetWide instead.
+func (array *ValueFloatArray) Get(index uint8) (EntryValue, error) {
+	return array.GetWide(uint32(index))
+}
+
+// Update satisfies EntryValueArray but can only reach the first 256
+// elements; callers that need the full range this type was built for
+// should use UpdateWide instead.
+func (array *ValueFloatArray) Update(index uint8, entry EntryValue) error {
+	return array.UpdateWide(uint32(index), entry)
+}
+
+func (array *ValueFloatArray) GetWide(index uint32) (EntryValue, error) {
+	if index >= uint32(len(array.elements)) {
+		return nil, ErrArrayIndexOutOfBounds
+	}
+	return array.elements[index], nil
+}
+
+func (array *ValueFloatArray) UpdateWide(index uint32, entry EntryValue) error {
+	float, ok := entry.(*ValueFloat)
+	if !ok {
+		return ErrEntryCastInvalid
+	}
+	if index >= uint32(len(array.elements)) {
+		return ErrArrayIndexOutOfBounds
+	}
+	return mergo.MergeWithOverwrite(array.elements[index], *float)
+}
+
+func (array *ValueFloatArray) Add(entry EntryValue) error {
+	float, ok := entry.(*ValueFloat)
+	if !ok {
+		return ErrEntryCastInvalid
+	}
+	if uint32(len(array.elements)) == math.MaxUint32 {
+		return ErrArrayOutOfSpace
+	}
+	array.elements = append(array.elements, float)
+	return nil
+}
+
+func (array *ValueFloatArray) GetRaw() []byte {
+	data := EncodeULEB128(uint32(len(array.elements)))
+	for _, element := range array.elements {
+		data = append(data, element.RawValue...)
+	}
+	return data
+}
+
+type ValueBooleanArrayWide struct {
+	elements []*ValueBoolean
+}
+
+func DecodeBooleanArrayWide(r io.Reader) (*ValueBooleanArrayWide, error) {
+	count, _, ulebErr := DecodeAndSaveULEB128(r)
+	if ulebErr != nil {
+		return nil, ulebErr
+	}
+	elements := make([]*ValueBoolean, count)
+	for i := range elements {
+		boolean, decodeErr := DecodeBoolean(r)
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+		elements[i] = boolean
+	}
+	return &ValueBooleanArrayWide{elements: elements}, nil
+}
+
+func BuildBooleanArrayWide(values []*ValueBoolean) *ValueBooleanArrayWide {
+	return &ValueBooleanArrayWide{elements: values}
+}
+
+// Get satisfies EntryValueArray but can only reach the first 256 elements;
+// callers that need the full range this type was built for should use
+// GetWide instead.
+func (array *ValueBooleanArrayWide) Get(index uint8) (EntryValue, error) {
+	return array.GetWide(uint32(index))
+}
+
+// Update satisfies EntryValueArray but can only reach the first 256
+// elements; callers that need the full range this type was built for
+// should use UpdateWide instead.
+func (array *ValueBooleanArrayWide) Update(index uint8, entry EntryValue) error {
+	return array.UpdateWide(uint32(index), entry)
+}
+
+func (array *ValueBooleanArrayWide) GetWide(index uint32) (EntryValue, error) {
+	if index >= uint32(len(array.elements)) {
+		return nil, ErrArrayIndexOutOfBounds
+	}
+	return array.elements[index], nil
+}
+
+func (array *ValueBooleanArrayWide) UpdateWide(index uint32, entry EntryValue) error {
+	boolean, ok := entry.(*ValueBoolean)
+	if !ok {
+		return ErrEntryCastInvalid
+	}
+	if index >= uint32(len(array.elements)) {
+		return ErrArrayIndexOutOfBounds
+	}
+	return mergo.MergeWithOverwrite(array.elements[index], *boolean)
+}
+
+func (array *ValueBooleanArrayWide) Add(entry EntryValue) error {
+	boolean, ok := entry.(*ValueBoolean)
+	if !ok {
+		return ErrEntryCastInvalid
+	}
+	if uint32(len(array.elements)) == math.MaxUint32 {
+		return ErrArrayOutOfSpace
+	}
+	array.elements = append(array.elements, boolean)
+	return nil
+}
+
+func (array *ValueBooleanArrayWide) GetRaw() []byte {
+	data := EncodeULEB128(uint32(len(array.elements)))
+	for _, element := range array.elements {
+		data = append(data, element.RawValue...)
+	}
+	return data
+}
+
+type ValueDoubleArrayWide struct {
+	elements []*ValueDouble
+}
+
+func DecodeDoubleArrayWide(r io.Reader) (*ValueDoubleArrayWide, error) {
+	count, _, ulebErr := DecodeAndSaveULEB128(r)
+	if ulebErr != nil {
+		return nil, ulebErr
+	}
+	elements := make([]*ValueDouble, count)
+	for i := range elements {
+		double, decodeErr := DecodeDouble(r)
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+		elements[i] = double
+	}
+	return &ValueDoubleArrayWide{elements: elements}, nil
+}
+
+func BuildDoubleArrayWide(values []*ValueDouble) *ValueDoubleArrayWide {
+	return &ValueDoubleArrayWide{elements: values}
+}
+
+// Get satisfies EntryValueArray but can only reach the first 256 elements;
+// callers that need the full range this type was built for should use
+// GetWide instead.
+func (array *ValueDoubleArrayWide) Get(index uint8) (EntryValue, error) {
+	return array.GetWide(uint32(index))
+}
+
+// Update satisfies EntryValueArray but can only reach the first 256
+// elements; callers that need the full range this type was built for
+// should use UpdateWide instead.
+func (array *ValueDoubleArrayWide) Update(index uint8, entry EntryValue) error {
+	return array.UpdateWide(uint32(index), entry)
+}
+
+func (array *ValueDoubleArrayWide) GetWide(index uint32) (EntryValue, error) {
+	if index >= uint32(len(array.elements)) {
+		return nil, ErrArrayIndexOutOfBounds
+	}
+	return array.elements[index], nil
+}
+
+func (array *ValueDoubleArrayWide) UpdateWide(index uint32, entry EntryValue) error {
+	double, ok := entry.(*ValueDouble)
+	if !ok {
+		return ErrEntryCastInvalid
+	}
+	if index >= uint32(len(array.elements)) {
+		return ErrArrayIndexOutOfBounds
+	}
+	return mergo.MergeWithOverwrite(array.elements[index], *double)
+}
+
+func (array *ValueDoubleArrayWide) Add(entry EntryValue) error {
+	double, ok := entry.(*ValueDouble)
+	if !ok {
+		return ErrEntryCastInvalid
+	}
+	if uint32(len(array.elements)) == math.MaxUint32 {
+		return ErrArrayOutOfSpace
+	}
+	array.elements = append(array.elements, double)
+	return nil
+}
+
+func (array *ValueDoubleArrayWide) GetRaw() []byte {
+	data := EncodeULEB128(uint32(len(array.elements)))
+	for _, element := range array.elements {
+		data = append(data, element.RawValue...)
+	}
+	return data
+}
+
+type ValueStringArrayWide struct {
+	elements []*ValueString
+}
+
+func DecodeStringArrayWide(r io.Reader) (*ValueStringArrayWide, error) {
+	count, _, ulebErr := DecodeAndSaveULEB128(r)
+	if ulebErr != nil {
+		return nil, ulebErr
+	}
+	elements := make([]*ValueString, count)
+	for i := range elements {
+		str, decodeErr := DecodeString(r)
+		if decodeErr != nil {
+			return nil, decodeErr
+		}
+		elements[i] = str
+	}
+	return &ValueStringArrayWide{elements: elements}, nil
+}
+
+func BuildStringArrayWide(values []*ValueString) *ValueStringArrayWide {
+	return &ValueStringArrayWide{elements: values}
+}
+
+// Get satisfies EntryValueArray but can only reach the first 256 elements;
+// callers that need the full range this type was built for should use
+// GetWide instead.
+func (array *ValueStringArrayWide) Get(index uint8) (EntryValue, error) {
+	return array.GetWide(uint32(index))
+}
+
+// Update satisfies EntryValueArray but can only reach the first 256
+// elements; callers that need the full range this type was built for
+// should use UpdateWide instead.
+func (array *ValueStringArrayWide) Update(index uint8, entry EntryValue) error {
+	return array.UpdateWide(uint32(index), entry)
+}
+
+func (array *ValueStringArrayWide) GetWide(index uint32) (EntryValue, error) {
+	if index >= uint32(len(array.elements)) {
+		return nil, ErrArrayIndexOutOfBounds
+	}
+	return array.elements[index], nil
+}
+
+func (array *ValueStringArrayWide) UpdateWide(index uint32, entry EntryValue) error {
+	str, ok := entry.(*ValueString)
+	if !ok {
+		return ErrEntryCastInvalid
+	}
+	if index >= uint32(len(array.elements)) {
+		return ErrArrayIndexOutOfBounds
+	}
+	return mergo.MergeWithOverwrite(array.elements[index], *str)
+}
+
+func (array *ValueStringArrayWide) Add(entry EntryValue) error {
+	str, ok := entry.(*ValueString)
+	if !ok {
+		return ErrEntryCastInvalid
+	}
+	if uint32(len(array.elements)) == math.MaxUint32 {
+		return ErrArrayOutOfSpace
+	}
+	array.elements = append(array.elements, str)
+	return nil
+}
+
+func (array *ValueStringArrayWide) GetRaw() []byte {
+	data := EncodeULEB128(uint32(len(array.elements)))
+	for _, element := range array.elements {
+		data = append(data, element.RawValue...)
+	}
+	return data
+}
@@ -0,0 +1,90 @@
+package ntgo
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestIntegerAndFloatRoundTrip(t *testing.T) {
+	integer, err := DecodeInteger(bytes.NewReader(BuildInteger(-42).RawValue))
+	if err != nil {
+		t.Fatalf("DecodeInteger: %v", err)
+	}
+	if integer.Value != -42 {
+		t.Errorf("got %d, want -42", integer.Value)
+	}
+
+	float, err := DecodeFloat(bytes.NewReader(BuildFloat(2.5).RawValue))
+	if err != nil {
+		t.Fatalf("DecodeFloat: %v", err)
+	}
+	if float.Value != 2.5 {
+		t.Errorf("got %v, want 2.5", float.Value)
+	}
+}
+
+func TestDoubleArrayWideRoundTrip(t *testing.T) {
+	values := []*ValueDouble{BuildDouble(1), BuildDouble(2), BuildDouble(3)}
+	array := BuildDoubleArrayWide(values)
+
+	decoded, err := DecodeDoubleArrayWide(bytes.NewReader(array.GetRaw()))
+	if err != nil {
+		t.Fatalf("DecodeDoubleArrayWide: %v", err)
+	}
+	for i, want := range values {
+		got, err := decoded.GetWide(uint32(i))
+		if err != nil {
+			t.Fatalf("GetWide(%d): %v", i, err)
+		}
+		if got.(*ValueDouble).Value != want.Value {
+			t.Errorf("element %d: got %v, want %v", i, got.(*ValueDouble).Value, want.Value)
+		}
+	}
+}
+
+func TestIntegerArrayGetWideBeyondUint8Range(t *testing.T) {
+	values := make([]*ValueInteger, 300)
+	for i := range values {
+		values[i] = BuildInteger(int64(i))
+	}
+	array := BuildIntegerArray(values)
+
+	got, err := array.GetWide(299)
+	if err != nil {
+		t.Fatalf("GetWide(299): %v", err)
+	}
+	if got.(*ValueInteger).Value != 299 {
+		t.Errorf("got %d, want 299", got.(*ValueInteger).Value)
+	}
+
+	if err := array.UpdateWide(299, BuildInteger(-1)); err != nil {
+		t.Fatalf("UpdateWide(299): %v", err)
+	}
+	got, _ = array.GetWide(299)
+	if got.(*ValueInteger).Value != -1 {
+		t.Errorf("after UpdateWide: got %d, want -1", got.(*ValueInteger).Value)
+	}
+}
+
+func TestCodecNT3RejectsNT4Types(t *testing.T) {
+	codec := NewCodec(NT3)
+	_, err := codec.DecodeValue(bytes.NewReader(BuildInteger(1).RawValue), EntryTypeInteger)
+	if err != ErrEntryNoSuchType {
+		t.Fatalf("got err %v, want ErrEntryNoSuchType", err)
+	}
+}
+
+func TestCodecValidateTypeGuardsEncodeSide(t *testing.T) {
+	codec := NewCodec(NT3)
+	if err := codec.ValidateType(EntryTypeBooleanArrWide); err != ErrEntryNoSuchType {
+		t.Fatalf("got err %v, want ErrEntryNoSuchType", err)
+	}
+	if err := codec.ValidateType(EntryTypeBoolean); err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+
+	nt4Codec := NewCodec(NT4)
+	if err := nt4Codec.ValidateType(EntryTypeBooleanArrWide); err != nil {
+		t.Fatalf("got err %v, want nil", err)
+	}
+}
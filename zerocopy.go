@@ -0,0 +1,169 @@
+package ntgo
+
+import (
+	"bufio"
+	"io"
+	"sync"
+)
+
+var scratchPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, 64)
+		return &buf
+	},
+}
+
+func getScratch(n int) *[]byte {
+	ptr := scratchPool.Get().(*[]byte)
+	if cap(*ptr) < n {
+		*ptr = make([]byte, n)
+	} else {
+		*ptr = (*ptr)[:n]
+	}
+	return ptr
+}
+
+func putScratch(ptr *[]byte) {
+	scratchPool.Put(ptr)
+}
+
+// reuseRawValue grows dst in place when it already has the capacity,
+// instead of handing back a freshly allocated slice.
+func reuseRawValue(dst []byte, data []byte) []byte {
+	if cap(dst) >= len(data) {
+		dst = dst[:len(data)]
+	} else {
+		dst = make([]byte, len(data))
+	}
+	copy(dst, data)
+	return dst
+}
+
+// decodeBooleanFrom, decodeDoubleFrom, decodeStringFrom and decodeRawFrom
+// read directly off r with no buffering of their own, so they are safe to
+// call repeatedly against a shared, long-lived stream: wrapping r in a
+// bufio.Reader here would read ahead past the current value and discard
+// whatever it buffered once the call returns.
+func decodeBooleanFrom(r io.Reader, dst *ValueBoolean) error {
+	scratch := getScratch(1)
+	defer putScratch(scratch)
+	if _, err := io.ReadFull(r, *scratch); err != nil {
+		return err
+	}
+	switch (*scratch)[0] {
+	case BoolFalse:
+		dst.Value = false
+	case BoolTrue:
+		dst.Value = true
+	default:
+		return ErrEntryDataInvalid
+	}
+	dst.RawValue = reuseRawValue(dst.RawValue, *scratch)
+	return nil
+}
+
+func decodeDoubleFrom(r io.Reader, dst *ValueDouble) error {
+	scratch := getScratch(8)
+	defer putScratch(scratch)
+	if _, err := io.ReadFull(r, *scratch); err != nil {
+		return err
+	}
+	dst.Value = BytesToFloat64(*scratch)
+	dst.RawValue = reuseRawValue(dst.RawValue, *scratch)
+	return nil
+}
+
+func decodeStringFrom(r io.Reader, dst *ValueString) error {
+	length, lengthRaw, err := DecodeAndSaveULEB128(r)
+	if err != nil {
+		return err
+	}
+	scratch := getScratch(int(length))
+	defer putScratch(scratch)
+	if _, err := io.ReadFull(r, *scratch); err != nil {
+		return err
+	}
+	dst.Value = string(*scratch)
+	dst.RawValue = reuseRawValue(dst.RawValue, append(lengthRaw, *scratch...))
+	return nil
+}
+
+func decodeRawFrom(r io.Reader, dst *ValueRaw) error {
+	length, lengthRaw, err := DecodeAndSaveULEB128(r)
+	if err != nil {
+		return err
+	}
+	scratch := getScratch(int(length))
+	defer putScratch(scratch)
+	if _, err := io.ReadFull(r, *scratch); err != nil {
+		return err
+	}
+	dst.Value = reuseRawValue(dst.Value, *scratch)
+	dst.RawValue = reuseRawValue(dst.RawValue, append(lengthRaw, *scratch...))
+	return nil
+}
+
+// Decoder buffers reads off r via bufio, reusing pooled scratch buffers and
+// the destination's existing RawValue backing array wherever possible. It
+// is meant to be kept alive across many DecodeXInto calls against the same
+// stream; callers that only need to decode a single value from an
+// already-framed []byte or io.Reader should use the package-level DecodeX
+// functions instead.
+type Decoder struct {
+	r *bufio.Reader
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r)}
+}
+
+func (dec *Decoder) DecodeBooleanInto(dst *ValueBoolean) error {
+	return decodeBooleanFrom(dec.r, dst)
+}
+
+func (dec *Decoder) DecodeDoubleInto(dst *ValueDouble) error {
+	return decodeDoubleFrom(dec.r, dst)
+}
+
+func (dec *Decoder) DecodeStringInto(dst *ValueString) error {
+	return decodeStringFrom(dec.r, dst)
+}
+
+func (dec *Decoder) DecodeRawInto(dst *ValueRaw) error {
+	return decodeRawFrom(dec.r, dst)
+}
+
+// Encoder buffers writes to w via bufio. Values are already held
+// fully-encoded in their RawValue field, so EncodeX writes straight through
+// without an intermediate append; call Flush once done encoding.
+type Encoder struct {
+	w *bufio.Writer
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: bufio.NewWriter(w)}
+}
+
+func (enc *Encoder) EncodeBoolean(v *ValueBoolean) error {
+	_, err := enc.w.Write(v.RawValue)
+	return err
+}
+
+func (enc *Encoder) EncodeDouble(v *ValueDouble) error {
+	_, err := enc.w.Write(v.RawValue)
+	return err
+}
+
+func (enc *Encoder) EncodeString(v *ValueString) error {
+	_, err := enc.w.Write(v.RawValue)
+	return err
+}
+
+func (enc *Encoder) EncodeRaw(v *ValueRaw) error {
+	_, err := enc.w.Write(v.RawValue)
+	return err
+}
+
+func (enc *Encoder) Flush() error {
+	return enc.w.Flush()
+}
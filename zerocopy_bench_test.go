@@ -0,0 +1,70 @@
+package ntgo
+
+import (
+	"bytes"
+	"testing"
+)
+
+// cyclicReader replays data forever, standing in for the long-lived
+// connection a Decoder is meant to be kept alive across.
+type cyclicReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *cyclicReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if r.pos >= len(r.data) {
+			r.pos = 0
+		}
+		copied := copy(p[n:], r.data[r.pos:])
+		n += copied
+		r.pos += copied
+	}
+	return n, nil
+}
+
+func BenchmarkDecodeDouble(b *testing.B) {
+	raw := BuildDouble(3.14159).RawValue
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := DecodeDouble(bytes.NewReader(raw)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecoderDecodeDoubleInto(b *testing.B) {
+	raw := BuildDouble(3.14159).RawValue
+	dec := NewDecoder(&cyclicReader{data: raw})
+	dst := &ValueDouble{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := dec.DecodeDoubleInto(dst); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeString(b *testing.B) {
+	raw := BuildString("left_drive_encoder").RawValue
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := DecodeString(bytes.NewReader(raw)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecoderDecodeStringInto(b *testing.B) {
+	raw := BuildString("left_drive_encoder").RawValue
+	dec := NewDecoder(&cyclicReader{data: raw})
+	dst := &ValueString{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := dec.DecodeStringInto(dst); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package ntgo
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecodeDoubleArrayFromSharedReader(t *testing.T) {
+	values := []*ValueDouble{BuildDouble(1), BuildDouble(2), BuildDouble(3)}
+	array := BuildDoubleArray(values)
+
+	decoded, err := DecodeDoubleArray(bytes.NewReader(array.GetRaw()))
+	if err != nil {
+		t.Fatalf("DecodeDoubleArray: %v", err)
+	}
+	if len(decoded.elements) != len(values) {
+		t.Fatalf("got %d elements, want %d", len(decoded.elements), len(values))
+	}
+	for i, want := range values {
+		if decoded.elements[i].Value != want.Value {
+			t.Errorf("element %d: got %v, want %v", i, decoded.elements[i].Value, want.Value)
+		}
+	}
+}
+
+func TestDecodeBooleanThenDoubleFromSameReader(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(BuildBoolean(true).RawValue)
+	buf.Write(BuildDouble(2.5).RawValue)
+
+	boolValue, err := DecodeBoolean(&buf)
+	if err != nil {
+		t.Fatalf("DecodeBoolean: %v", err)
+	}
+	if !boolValue.Value {
+		t.Errorf("got %v, want true", boolValue.Value)
+	}
+
+	doubleValue, err := DecodeDouble(&buf)
+	if err != nil {
+		t.Fatalf("DecodeDouble: %v", err)
+	}
+	if doubleValue.Value != 2.5 {
+		t.Errorf("got %v, want 2.5", doubleValue.Value)
+	}
+}
+
+func TestDecoderDecodesMultipleValuesAcrossCalls(t *testing.T) {
+	var buf bytes.Buffer
+	buf.Write(BuildString("a").RawValue)
+	buf.Write(BuildString("bb").RawValue)
+	buf.Write(BuildString("ccc").RawValue)
+
+	dec := NewDecoder(&buf)
+	want := []string{"a", "bb", "ccc"}
+	for _, w := range want {
+		dst := &ValueString{}
+		if err := dec.DecodeStringInto(dst); err != nil {
+			t.Fatalf("DecodeStringInto: %v", err)
+		}
+		if dst.Value != w {
+			t.Errorf("got %q, want %q", dst.Value, w)
+		}
+	}
+}